@@ -0,0 +1,195 @@
+package swfsclient
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// assignPoolKey groups pooled fids by the parameters they were assigned
+// with, since a fid from one collection/ttl/replication/dataCenter isn't
+// interchangeable with another.
+type assignPoolKey struct {
+	Collection  string
+	TTL         string
+	Replication string
+	DataCenter  string
+}
+
+// AssignPool pre-reserves batches of fids via Assign(count=N) per
+// (collection, ttl, replication, dataCenter) tuple, refilling in the
+// background as they run low. Use SwfsClient.EnableAssignPool /
+// SwfsClient.GetFID rather than constructing one directly.
+type AssignPool struct {
+	client       *SwfsClient
+	poolSize     int
+	lowWaterMark int
+
+	mu    sync.Mutex
+	fids  map[assignPoolKey][]string
+	fills map[assignPoolKey]bool // refill already in flight
+}
+
+func newAssignPool(c *SwfsClient, poolSize, lowWaterMark int) *AssignPool {
+	if poolSize <= 0 {
+		poolSize = 100
+	}
+	if lowWaterMark <= 0 {
+		lowWaterMark = poolSize / 4
+	}
+
+	return &AssignPool{
+		client:       c,
+		poolSize:     poolSize,
+		lowWaterMark: lowWaterMark,
+		fids:         make(map[assignPoolKey][]string),
+		fills:        make(map[assignPoolKey]bool),
+	}
+}
+
+// GetFID returns a pre-reserved fid for key, blocking on a synchronous
+// refill only if the pool for key is currently empty.
+func (p *AssignPool) GetFID(collection, ttl, replication, dataCenter string) (string, error) {
+	if err := validateTTL(ttl); err != nil {
+		return "", err
+	}
+	key := assignPoolKey{Collection: collection, TTL: ttl, Replication: replication, DataCenter: dataCenter}
+
+	fid, remaining, found := p.take(key)
+	if !found {
+		if err := p.refill(key); err != nil {
+			return "", err
+		}
+		fid, remaining, found = p.take(key)
+		if !found {
+			return "", fmt.Errorf("assign pool: no fids available for %+v", key)
+		}
+	}
+
+	if remaining < p.lowWaterMark {
+		p.refillAsync(key)
+	}
+	return fid, nil
+}
+
+// take pops one fid off key's pool, if any are left.
+func (p *AssignPool) take(key assignPoolKey) (fid string, remaining int, found bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fids := p.fids[key]
+	if len(fids) == 0 {
+		return "", 0, false
+	}
+
+	fid, p.fids[key] = fids[0], fids[1:]
+	return fid, len(p.fids[key]), true
+}
+
+// refillAsync refills key's pool in the background, at most once at a time.
+func (p *AssignPool) refillAsync(key assignPoolKey) {
+	p.mu.Lock()
+	if p.fills[key] {
+		p.mu.Unlock()
+		return
+	}
+	p.fills[key] = true
+	p.mu.Unlock()
+
+	go func() {
+		defer func() {
+			p.mu.Lock()
+			p.fills[key] = false
+			p.mu.Unlock()
+		}()
+		_ = p.refill(key)
+	}()
+}
+
+// refill assigns a fresh batch of fids for key, growing volumes first if
+// the master reports none are free.
+func (p *AssignPool) refill(key assignPoolKey) error {
+	args := normalize(nil, key.Collection, key.TTL)
+	if key.Replication != "" {
+		args.Set(ParamAssignReplication, key.Replication)
+	}
+	if key.DataCenter != "" {
+		args.Set(ParamAssignDataCenter, key.DataCenter)
+	}
+	args.Set(ParamAssignCount, strconv.Itoa(p.poolSize))
+
+	result, err := p.client.Assign(args)
+	if err != nil && isNoFreeVolumesError(err) {
+		if growErr := p.client.Grow(0, key.Collection, key.TTL, key.Replication, key.DataCenter); growErr != nil {
+			return growErr
+		}
+		result, err = p.client.Assign(args)
+	}
+	if err != nil {
+		return err
+	}
+
+	fids, err := expandFileIDs(result.FileID, result.Count)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.fids[key] = append(p.fids[key], fids...)
+	p.mu.Unlock()
+	return nil
+}
+
+func isNoFreeVolumesError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "no free volumes")
+}
+
+// expandFileIDs derives the count fids a count>1 Assign call reserved,
+// starting from the base fid it returned. SeaweedFS hands back one fid per
+// batch and expects callers to increment the needle key for the rest,
+// keeping the volume ID and cookie unchanged.
+func expandFileIDs(baseFileID string, count int) ([]string, error) {
+	parts := strings.SplitN(baseFileID, ",", 2)
+	if len(parts) != 2 || len(parts[1]) <= 8 {
+		return nil, fmt.Errorf("assign pool: invalid fileID %q", baseFileID)
+	}
+	volumeID := parts[0]
+	cookie := parts[1][len(parts[1])-8:]
+	keyHex := parts[1][:len(parts[1])-8]
+
+	key, err := strconv.ParseUint(keyHex, 16, 64)
+	if err != nil {
+		return nil, fmt.Errorf("assign pool: invalid fileID %q: %v", baseFileID, err)
+	}
+
+	fids := make([]string, count)
+	for i := 0; i < count; i++ {
+		fids[i] = fmt.Sprintf("%s,%0*x%s", volumeID, len(keyHex), key+uint64(i), cookie)
+	}
+	return fids, nil
+}
+
+// validTTLUnits are the unit suffixes SeaweedFS accepts, per SwFile.TTL's
+// doc comment: 3m, 4h, 5d, 6w, 7M, 8y.
+const validTTLUnits = "mhdwMy"
+
+// validateTTL checks ttl client-side instead of sending it blindly to
+// /dir/assign.
+func validateTTL(ttl string) error {
+	if ttl == "" {
+		return nil
+	}
+	if len(ttl) < 2 {
+		return fmt.Errorf("invalid ttl %q", ttl)
+	}
+
+	unit := ttl[len(ttl)-1]
+	if !strings.ContainsRune(validTTLUnits, rune(unit)) {
+		return fmt.Errorf("invalid ttl %q: unit must be one of %s", ttl, validTTLUnits)
+	}
+	if _, err := strconv.Atoi(ttl[:len(ttl)-1]); err != nil {
+		return fmt.Errorf("invalid ttl %q: %v", ttl, err)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package swfsclient
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTTL(t *testing.T) {
+	require.Nil(t, validateTTL(""))
+	require.Nil(t, validateTTL("3m"))
+	require.Nil(t, validateTTL("4h"))
+	require.Nil(t, validateTTL("5d"))
+	require.Nil(t, validateTTL("6w"))
+	require.Nil(t, validateTTL("7M"))
+	require.Nil(t, validateTTL("8y"))
+
+	require.NotNil(t, validateTTL("m"))
+	require.NotNil(t, validateTTL("3x"))
+	require.NotNil(t, validateTTL("xm"))
+}
+
+func TestExpandFileIDs(t *testing.T) {
+	fids, err := expandFileIDs("3,01637037d6", 3)
+	require.Nil(t, err)
+	require.Equal(t, []string{"3,01637037d6", "3,02637037d6", "3,03637037d6"}, fids)
+}
@@ -0,0 +1,190 @@
+package swfsclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// defaultChunkSize is used by UploadLargeFile when chunkSize <= 0.
+const defaultChunkSize = 8 * 1024 * 1024
+
+// chunkInfo describes one chunk of a chunk-manifest file.
+type chunkInfo struct {
+	Fid    string `json:"fid"`
+	Offset int64  `json:"offset"`
+	Size   int64  `json:"size"`
+	ETag   string `json:"etag"`
+}
+
+// chunkManifest is stored as a small JSON object under its own fid, in
+// place of the file's actual content, mirroring SeaweedFS's own
+// filechunk_manifest design.
+type chunkManifest struct {
+	Name          string      `json:"name"`
+	Mime          string      `json:"mime"`
+	Size          int64       `json:"size"`
+	Chunks        []chunkInfo `json:"chunks"`
+	ChunkManifest bool        `json:"chunk_manifest"`
+}
+
+// UploadLargeFile splits f into chunks of chunkSize bytes (defaultChunkSize
+// if <= 0), reserves one fid per chunk with a single batched Assign
+// (count=N, like AssignPool.refill), uploads each chunk to its reserved
+// fid in parallel, and finally writes a chunk manifest recording their
+// order. Use DownloadLarge to fetch a file uploaded this way. Unlike
+// UploadSwFile, the input is not truncated at c.maxFileSize. f.FileSize
+// must be known up front, since it's what N is computed from.
+func (c *SwfsClient) UploadLargeFile(f *SwFile, chunkSize int64) (manifestResult *AssignResult, err error) {
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+	if f.FileSize <= 0 {
+		return nil, fmt.Errorf("chunked upload: FileSize must be known and positive, got %d", f.FileSize)
+	}
+
+	chunkCount := int((f.FileSize + chunkSize - 1) / chunkSize)
+	args := normalize(nil, f.Collection, f.TTL)
+	args.Set(ParamAssignCount, strconv.Itoa(chunkCount))
+	assignResult, err := c.Assign(args)
+	if err != nil {
+		return nil, err
+	}
+	fids, err := expandFileIDs(assignResult.FileID, chunkCount)
+	if err != nil {
+		return nil, err
+	}
+
+	base := *c.master
+	base.Host = assignResult.URL
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		chunks   []chunkInfo
+		offset   int64
+		index    int
+		firstErr error
+	)
+
+	for {
+		buf := make([]byte, chunkSize)
+		n, readErr := io.ReadFull(f.Reader, buf)
+		if n > 0 {
+			if index >= len(fids) {
+				return nil, fmt.Errorf("chunked upload: read more than the %d chunks reserved for FileSize %d", len(fids), f.FileSize)
+			}
+			fid := fids[index]
+
+			wg.Add(1)
+			go func(chunkIndex int, chunkOffset int64, fid string, data []byte) {
+				defer wg.Done()
+
+				chunkName := fmt.Sprintf("%s-chunk-%d", f.FileName, chunkIndex)
+
+				v, _, uploadErr := c.client.upload(encodeURI(base, fid, normalize(nil, f.Collection, f.TTL)), chunkName, bytes.NewReader(data), f.MimeType)
+				if uploadErr != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = uploadErr
+					}
+					mu.Unlock()
+					return
+				}
+
+				uploadResult := UploadResult{}
+				if unmarshalErr := json.Unmarshal(v, &uploadResult); unmarshalErr != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = unmarshalErr
+					}
+					mu.Unlock()
+					return
+				}
+
+				mu.Lock()
+				chunks = append(chunks, chunkInfo{Fid: fid, Offset: chunkOffset, Size: int64(n), ETag: uploadResult.Etag})
+				mu.Unlock()
+			}(index, offset, fid, buf[:n])
+
+			offset += int64(n)
+			index++
+		}
+
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		} else if readErr != nil {
+			return nil, readErr
+		}
+	}
+
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	sort.Slice(chunks, func(i, j int) bool { return chunks[i].Offset < chunks[j].Offset })
+
+	manifest := chunkManifest{
+		Name:          f.FileName,
+		Mime:          f.MimeType,
+		Size:          offset,
+		Chunks:        chunks,
+		ChunkManifest: true,
+	}
+	manifestBytes, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	manifestResult, err = c.Assign(normalize(nil, f.Collection, f.TTL))
+	if err != nil {
+		return nil, err
+	}
+
+	manifestBase := *c.master
+	manifestBase.Host = manifestResult.URL
+	_, _, err = c.client.upload(encodeURI(manifestBase, manifestResult.FileID, normalize(nil, f.Collection, f.TTL)), f.FileName, bytes.NewReader(manifestBytes), "application/json")
+	return manifestResult, err
+}
+
+// DownloadLarge downloads a file that may have been uploaded with
+// UploadLargeFile. It fetches fileID, and if the content is a chunk
+// manifest, streams each chunk through callback in order; otherwise it
+// behaves like Download.
+func (c *SwfsClient) DownloadLarge(fileID string, args url.Values, callback func(io.Reader) error) (string, error) {
+	var manifest chunkManifest
+	var isManifest bool
+
+	fileName, err := c.Download(fileID, args, func(r io.Reader) error {
+		data, readErr := ioutil.ReadAll(r)
+		if readErr != nil {
+			return readErr
+		}
+		if json.Unmarshal(data, &manifest) == nil && manifest.ChunkManifest {
+			isManifest = true
+			return nil
+		}
+		return callback(bytes.NewReader(data))
+	})
+	if err != nil {
+		return "", err
+	}
+	if !isManifest {
+		return fileName, nil
+	}
+
+	sort.Slice(manifest.Chunks, func(i, j int) bool { return manifest.Chunks[i].Offset < manifest.Chunks[j].Offset })
+	for _, chunk := range manifest.Chunks {
+		if _, err = c.Download(chunk.Fid, args, callback); err != nil {
+			return "", err
+		}
+	}
+	return manifest.Name, nil
+}
@@ -0,0 +1,40 @@
+package swfsclient
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunkManifestRoundTrip(t *testing.T) {
+	manifest := chunkManifest{
+		Name: "big.bin",
+		Mime: "application/octet-stream",
+		Size: 24,
+		Chunks: []chunkInfo{
+			{Fid: "3,02637037d6", Offset: 8, Size: 8, ETag: "b"},
+			{Fid: "3,01637037d6", Offset: 0, Size: 8, ETag: "a"},
+			{Fid: "3,03637037d6", Offset: 16, Size: 8, ETag: "c"},
+		},
+		ChunkManifest: true,
+	}
+
+	data, err := json.Marshal(manifest)
+	require.Nil(t, err)
+
+	var decoded chunkManifest
+	require.Nil(t, json.Unmarshal(data, &decoded))
+	require.True(t, decoded.ChunkManifest)
+	require.Equal(t, manifest.Name, decoded.Name)
+	require.Equal(t, manifest.Size, decoded.Size)
+	require.ElementsMatch(t, manifest.Chunks, decoded.Chunks)
+
+	// DownloadLarge relies on chunks coming back out in offset order
+	// regardless of how they were assembled.
+	sort.Slice(decoded.Chunks, func(i, j int) bool { return decoded.Chunks[i].Offset < decoded.Chunks[j].Offset })
+	require.Equal(t, []string{"3,01637037d6", "3,02637037d6", "3,03637037d6"}, []string{
+		decoded.Chunks[0].Fid, decoded.Chunks[1].Fid, decoded.Chunks[2].Fid,
+	})
+}
@@ -5,6 +5,14 @@ import "fmt"
 var (
 	// ErrFileNotFound return file not found error
 	ErrFileNotFound = fmt.Errorf("File not found")
+
+	// errFilerNotConfigured is returned by the *Path helpers when no Filer
+	// was attached via SetFiler.
+	errFilerNotConfigured = fmt.Errorf("filer is not configured, call SetFiler first")
+
+	// errAssignPoolNotEnabled is returned by GetFID when EnableAssignPool
+	// has not been called.
+	errAssignPoolNotEnabled = fmt.Errorf("assign pool is not enabled, call EnableAssignPool first")
 )
 
 const (
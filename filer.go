@@ -0,0 +1,64 @@
+package swfsclient
+
+import (
+	"io"
+	"net/url"
+
+	"github.com/simpcl/seaweedfsclient/filer"
+)
+
+// SetFiler attaches a Filer to c so that UploadFilePath/DownloadPath/
+// DeleteFilePath can resolve logical paths to file IDs instead of callers
+// having to track fids themselves.
+func (c *SwfsClient) SetFiler(f *filer.Filer) {
+	c.filer = f
+}
+
+// UploadFilePath uploads f and records path->FileID in the attached Filer.
+func (c *SwfsClient) UploadFilePath(path string, f *SwFile) (assignResult *AssignResult, err error) {
+	if c.filer == nil {
+		return nil, errFilerNotConfigured
+	}
+
+	assignResult, err = c.UploadSwFile(f)
+	if err != nil {
+		return
+	}
+
+	err = c.filer.PutPath(path, assignResult.FileID)
+	return
+}
+
+// DownloadPath resolves path to a FileID via the attached Filer and
+// downloads it, same as Download.
+func (c *SwfsClient) DownloadPath(path string, args url.Values, callback func(io.Reader) error) (string, error) {
+	if c.filer == nil {
+		return "", errFilerNotConfigured
+	}
+
+	fileID, err := c.filer.GetPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	return c.Download(fileID, args, callback)
+}
+
+// DeleteFilePath resolves path via the attached Filer, deletes the
+// underlying file and removes the path mapping.
+func (c *SwfsClient) DeleteFilePath(path string, args url.Values) error {
+	if c.filer == nil {
+		return errFilerNotConfigured
+	}
+
+	fileID, err := c.filer.GetPath(path)
+	if err != nil {
+		return err
+	}
+
+	if err = c.DeleteFile(fileID, args); err != nil {
+		return err
+	}
+
+	return c.filer.DeletePath(path)
+}
@@ -0,0 +1,59 @@
+// Package filer provides a pluggable path->FileID index on top of a
+// SwfsClient, mirroring the server-side filer design so that human-readable
+// paths can be resolved to SeaweedFS file IDs across multiple clients.
+package filer
+
+import "errors"
+
+// ErrPathNotFound is returned when a path has no known FileID mapping.
+var ErrPathNotFound = errors.New("filer: path not found")
+
+// Backend is the pluggable key-value store backing a Filer. Implementations
+// are expected to be safe for concurrent use, e.g. wrapping Redis,
+// Cassandra or etcd.
+type Backend interface {
+	// Put stores the FileID for path, overwriting any existing mapping.
+	Put(path, fileID string) error
+
+	// Get returns the FileID stored for path, or ErrPathNotFound.
+	Get(path string) (fileID string, err error)
+
+	// Delete removes the mapping for path. Deleting a missing path is not
+	// an error.
+	Delete(path string) error
+
+	// List returns up to limit paths under dir, in backend-defined order.
+	// A limit <= 0 means no limit.
+	List(dir string, limit int) ([]string, error)
+}
+
+// Filer stores a path->FileID mapping in a pluggable Backend so that
+// multiple clients can resolve human-readable paths to SeaweedFS file IDs.
+type Filer struct {
+	backend Backend
+}
+
+// New creates a Filer backed by backend.
+func New(backend Backend) *Filer {
+	return &Filer{backend: backend}
+}
+
+// PutPath records that path maps to fileID.
+func (f *Filer) PutPath(path, fileID string) error {
+	return f.backend.Put(path, fileID)
+}
+
+// GetPath resolves path to the FileID it was last stored with.
+func (f *Filer) GetPath(path string) (fileID string, err error) {
+	return f.backend.Get(path)
+}
+
+// DeletePath removes the mapping for path.
+func (f *Filer) DeletePath(path string) error {
+	return f.backend.Delete(path)
+}
+
+// ListPath lists up to limit paths under dir.
+func (f *Filer) ListPath(dir string, limit int) ([]string, error) {
+	return f.backend.List(dir, limit)
+}
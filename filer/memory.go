@@ -0,0 +1,67 @@
+package filer
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemoryBackend is a Backend that keeps the path->FileID mapping in memory.
+// It is meant as a reference implementation and for tests; production use
+// should plug in a Redis, Cassandra or etcd-backed Backend instead.
+type MemoryBackend struct {
+	mu    sync.RWMutex
+	paths map[string]string
+}
+
+// NewMemoryBackend creates an empty MemoryBackend.
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{paths: make(map[string]string)}
+}
+
+// Put implements Backend.
+func (b *MemoryBackend) Put(path, fileID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.paths[path] = fileID
+	return nil
+}
+
+// Get implements Backend.
+func (b *MemoryBackend) Get(path string) (string, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	fileID, found := b.paths[path]
+	if !found {
+		return "", ErrPathNotFound
+	}
+	return fileID, nil
+}
+
+// Delete implements Backend.
+func (b *MemoryBackend) Delete(path string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.paths, path)
+	return nil
+}
+
+// List implements Backend.
+func (b *MemoryBackend) List(dir string, limit int) ([]string, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	prefix := strings.TrimSuffix(dir, "/") + "/"
+	matches := make([]string, 0)
+	for path := range b.paths {
+		if strings.HasPrefix(path, prefix) {
+			matches = append(matches, path)
+		}
+	}
+	sort.Strings(matches)
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
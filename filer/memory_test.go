@@ -0,0 +1,59 @@
+package filer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryBackendPutGetDelete(t *testing.T) {
+	b := NewMemoryBackend()
+
+	_, err := b.Get("/bucket/key")
+	require.Equal(t, ErrPathNotFound, err)
+
+	require.Nil(t, b.Put("/bucket/key", "3,01637037d6"))
+	fileID, err := b.Get("/bucket/key")
+	require.Nil(t, err)
+	require.Equal(t, "3,01637037d6", fileID)
+
+	require.Nil(t, b.Delete("/bucket/key"))
+	_, err = b.Get("/bucket/key")
+	require.Equal(t, ErrPathNotFound, err)
+
+	// Deleting a missing path is not an error.
+	require.Nil(t, b.Delete("/bucket/key"))
+}
+
+func TestMemoryBackendList(t *testing.T) {
+	b := NewMemoryBackend()
+	require.Nil(t, b.Put("/bucket/a", "1,1"))
+	require.Nil(t, b.Put("/bucket/b", "1,2"))
+	require.Nil(t, b.Put("/bucket/c", "1,3"))
+	require.Nil(t, b.Put("/other/a", "1,4"))
+
+	paths, err := b.List("/bucket", 0)
+	require.Nil(t, err)
+	require.Equal(t, []string{"/bucket/a", "/bucket/b", "/bucket/c"}, paths)
+
+	paths, err = b.List("/bucket", 2)
+	require.Nil(t, err)
+	require.Equal(t, []string{"/bucket/a", "/bucket/b"}, paths)
+}
+
+func TestFilerPutGetListDelete(t *testing.T) {
+	f := New(NewMemoryBackend())
+
+	require.Nil(t, f.PutPath("/bucket/key", "3,01637037d6"))
+	fileID, err := f.GetPath("/bucket/key")
+	require.Nil(t, err)
+	require.Equal(t, "3,01637037d6", fileID)
+
+	paths, err := f.ListPath("/bucket", 0)
+	require.Nil(t, err)
+	require.Equal(t, []string{"/bucket/key"}, paths)
+
+	require.Nil(t, f.DeletePath("/bucket/key"))
+	_, err = f.GetPath("/bucket/key")
+	require.Equal(t, ErrPathNotFound, err)
+}
@@ -0,0 +1,108 @@
+package swfsclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+
+	"google.golang.org/grpc"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/volume_server_pb"
+)
+
+// grpcPortOffset is the conventional gap between a volume server's HTTP
+// port and its gRPC port, e.g. HTTP 8080 -> gRPC 18080.
+const grpcPortOffset = 10000
+
+// volumeServerGRPCAddress derives host:grpcPort from a volume location's
+// HTTP host:port.
+func volumeServerGRPCAddress(httpHostPort string) (string, error) {
+	host, portStr, err := net.SplitHostPort(httpHostPort)
+	if err != nil {
+		return "", err
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return "", err
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(port+grpcPortOffset)), nil
+}
+
+func (c *SwfsClient) grpcDialOption() grpc.DialOption {
+	if c.GrpcDialOption != nil {
+		return c.GrpcDialOption
+	}
+	return grpc.WithInsecure()
+}
+
+// DownloadGRPC downloads a file by fetching it from the volume server over
+// gRPC (volume_server_pb.FileGet) instead of HTTP. Used directly, or
+// transparently via Download when UseGRPC is set. Like Download's HTTP
+// path, it retries once with the volume-location cache busted on failure,
+// so a rebalance that moved fileID's volume doesn't wedge it on a stale
+// cache entry. Unlike Download's HTTP path, the returned fileName is
+// always "" - volume_server_pb.FileGet doesn't carry one.
+func (c *SwfsClient) DownloadGRPC(fileID string, args url.Values, callback func(io.Reader) error) (string, error) {
+	var withCache = true
+	var err error
+	for retry := 2; retry > 0; retry-- {
+		err = c.downloadGRPCOnce(fileID, args, withCache, callback)
+		if err == nil {
+			return "", nil
+		}
+		withCache = false
+	}
+	return "", err
+}
+
+func (c *SwfsClient) downloadGRPCOnce(fileID string, args url.Values, withCache bool, callback func(io.Reader) error) error {
+	vls, err := c.GetVolumeLocationsFromFileID(fileID, args, withCache)
+	if err != nil {
+		return err
+	}
+
+	grpcAddress, err := volumeServerGRPCAddress(vls.Head().URL)
+	if err != nil {
+		return err
+	}
+
+	conn, err := grpc.Dial(grpcAddress, c.grpcDialOption())
+	if err != nil {
+		return fmt.Errorf("dial volume server %s: %v", grpcAddress, err)
+	}
+	defer conn.Close()
+
+	client := volume_server_pb.NewVolumeServerClient(conn)
+	stream, err := client.FileGet(context.Background(), &volume_server_pb.FileGetRequest{
+		FileId: fileID,
+	})
+	if err != nil {
+		return err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		var streamErr error
+		for {
+			resp, recvErr := stream.Recv()
+			if recvErr == io.EOF {
+				break
+			}
+			if recvErr != nil {
+				streamErr = recvErr
+				break
+			}
+			if _, streamErr = pw.Write(resp.Data); streamErr != nil {
+				break
+			}
+		}
+		pw.CloseWithError(streamErr)
+	}()
+
+	return callback(pr)
+}
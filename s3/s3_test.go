@@ -0,0 +1,124 @@
+package s3
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/simpcl/seaweedfsclient"
+	"github.com/simpcl/seaweedfsclient/filer"
+)
+
+// fakeSwfsServer is a minimal stand-in for a SeaweedFS master+volume server:
+// /dir/assign and /dir/lookup both point back at the server itself, uploads
+// are accepted and echoed back with uploadSize (set by the caller to the
+// exact byte count it's about to upload, since faking the real upload wire
+// format isn't worth it here), and GET/HEAD on a fid serve whatever bytes
+// were last stored in body.
+type fakeSwfsServer struct {
+	*httptest.Server
+	assigns    int32
+	uploadSize int64
+	body       []byte
+}
+
+func newFakeSwfsServer(t *testing.T) *fakeSwfsServer {
+	s := &fakeSwfsServer{}
+	mux := http.NewServeMux()
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	host := strings.TrimPrefix(s.URL, "http://")
+	mux.HandleFunc("/dir/assign", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&s.assigns, 1)
+		fmt.Fprintf(w, `{"count":1,"fid":"3,%08x01020304","url":%q,"publicUrl":%q}`, n, host, host)
+	})
+	mux.HandleFunc("/dir/lookup", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"volumeId":"3","locations":[{"url":%q,"publicUrl":%q}]}`, host, host)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost, http.MethodPut:
+			_, _ = ioutil.ReadAll(r.Body)
+			fmt.Fprintf(w, `{"name":"object","size":%d,"eTag":"etag"}`, s.uploadSize)
+		case http.MethodHead:
+			w.Header().Set("Content-Length", strconv.FormatInt(int64(len(s.body)), 10))
+		case http.MethodDelete:
+			fmt.Fprint(w, `{}`)
+		default: // GET
+			w.Write(s.body)
+		}
+	})
+
+	return s
+}
+
+func newTestClient(t *testing.T) (*Client, *fakeSwfsServer) {
+	server := newFakeSwfsServer(t)
+
+	swfs, err := seaweedfsclient.NewSwfsClient(server.URL, server.Client(), 0)
+	require.Nil(t, err)
+
+	f := filer.New(filer.NewMemoryBackend())
+
+	return New(swfs, f), server
+}
+
+func TestClientPutGetHeadDeleteObject(t *testing.T) {
+	c, server := newTestClient(t)
+
+	data := []byte("hello object")
+	server.uploadSize = int64(len(data))
+
+	putOut, err := c.PutObject("mybucket", "a/b.txt", strings.NewReader(string(data)))
+	require.Nil(t, err)
+	require.Equal(t, "etag", putOut.ETag)
+
+	// The fake server doesn't actually persist uploaded bytes against a
+	// fid, so point subsequent GET/HEAD calls at what was just "uploaded".
+	server.body = data
+
+	getOut, err := c.GetObject("mybucket", "a/b.txt")
+	require.Nil(t, err)
+	defer getOut.Body.Close()
+	got, err := ioutil.ReadAll(getOut.Body)
+	require.Nil(t, err)
+	require.Equal(t, data, got)
+	require.EqualValues(t, len(data), getOut.ContentLength)
+
+	headOut, err := c.HeadObject("mybucket", "a/b.txt")
+	require.Nil(t, err)
+	require.EqualValues(t, len(data), headOut.ContentLength)
+
+	require.Nil(t, c.DeleteObject("mybucket", "a/b.txt"))
+
+	_, err = c.GetObject("mybucket", "a/b.txt")
+	require.NotNil(t, err)
+}
+
+func TestClientListObjectsV2(t *testing.T) {
+	c, server := newTestClient(t)
+	server.uploadSize = 1
+
+	for _, key := range []string{"a.txt", "b.txt", "c.txt"} {
+		_, err := c.PutObject("mybucket", key, strings.NewReader("x"))
+		require.Nil(t, err)
+	}
+
+	out, err := c.ListObjectsV2("mybucket", "", "/", "", 0)
+	require.Nil(t, err)
+	require.ElementsMatch(t, []string{"a.txt", "b.txt", "c.txt"}, out.Keys)
+	require.False(t, out.IsTruncated)
+
+	out, err = c.ListObjectsV2("mybucket", "", "/", "", 2)
+	require.Nil(t, err)
+	require.Len(t, out.Keys, 2)
+	require.True(t, out.IsTruncated)
+}
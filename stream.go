@@ -0,0 +1,201 @@
+package swfsclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+)
+
+// UploadStream uploads reader without requiring its size upfront, chunking
+// on the fly with the same manifest format as UploadLargeFile. Useful for
+// piping stdin, HTTP request bodies, or compressed streams where the
+// decompressed size isn't known ahead of time. Unlike UploadLargeFile,
+// which reserves every chunk fid in one batched Assign(count=N) up front,
+// the chunk count here isn't known until reader is exhausted, so this
+// falls back to one Assign per chunk - the same approach uploadResumable
+// uses, via the shared uploadChunksUnsized, minus the state file.
+func (c *SwfsClient) UploadStream(reader io.Reader, name, collection, ttl string) (*AssignResult, error) {
+	return c.uploadChunksUnsized(reader, name, "application/octet-stream", collection, ttl, 0, 0, nil, nil)
+}
+
+// resumableUploadState is the small JSON state file ResumableUpload and
+// ResumeUpload use to track progress, so an interrupted large upload can
+// pick up without re-sending completed chunks.
+type resumableUploadState struct {
+	UploadID       string      `json:"uploadId"`
+	Name           string      `json:"name"`
+	Mime           string      `json:"mime"`
+	Collection     string      `json:"collection"`
+	TTL            string      `json:"ttl"`
+	ChunkSize      int64       `json:"chunkSize"`
+	Offset         int64       `json:"offset"`
+	ChunksUploaded []chunkInfo `json:"chunksUploaded"`
+	Done           bool        `json:"done"`
+	ManifestFid    string      `json:"manifestFid,omitempty"`
+}
+
+// ResumableUpload starts a chunked upload of reader and records its
+// progress to stateFile after every chunk. If the process dies partway
+// through, resume it with ResumeUpload(stateFile, reader) instead of
+// starting over.
+func (c *SwfsClient) ResumableUpload(reader io.Reader, name, collection, ttl string, chunkSize int64) (manifestResult *AssignResult, stateFile string, err error) {
+	state := &resumableUploadState{
+		UploadID:   fmt.Sprintf("upload-%d", time.Now().UnixNano()),
+		Name:       name,
+		Collection: collection,
+		TTL:        ttl,
+		ChunkSize:  chunkSize,
+	}
+
+	stateFile = state.UploadID + ".swfsupload"
+	if err = saveResumableState(stateFile, state); err != nil {
+		return nil, "", err
+	}
+
+	manifestResult, err = c.uploadResumable(reader, state, stateFile)
+	return
+}
+
+// ResumeUpload continues a ResumableUpload from stateFile. reader must
+// yield the same bytes as the original upload, starting from its
+// beginning; ResumeUpload skips over the bytes already recorded as
+// uploaded before sending anything new.
+func (c *SwfsClient) ResumeUpload(stateFile string, reader io.Reader) (*AssignResult, error) {
+	state, err := loadResumableState(stateFile)
+	if err != nil {
+		return nil, err
+	}
+	if state.Done {
+		return nil, fmt.Errorf("resumable upload %s is already complete", state.UploadID)
+	}
+
+	if state.Offset > 0 {
+		if _, err = io.CopyN(ioutil.Discard, reader, state.Offset); err != nil {
+			return nil, fmt.Errorf("resumable upload %s: reader is shorter than the %d bytes already uploaded: %v", state.UploadID, state.Offset, err)
+		}
+	}
+
+	return c.uploadResumable(reader, state, stateFile)
+}
+
+// uploadResumable does the actual chunked upload for both ResumableUpload
+// and ResumeUpload, persisting state after each chunk completes.
+func (c *SwfsClient) uploadResumable(reader io.Reader, state *resumableUploadState, stateFile string) (manifestResult *AssignResult, err error) {
+	mimeType := state.Mime
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+
+	manifestResult, err = c.uploadChunksUnsized(reader, state.Name, mimeType, state.Collection, state.TTL, state.ChunkSize, state.Offset, state.ChunksUploaded,
+		func(chunk chunkInfo) error {
+			state.ChunksUploaded = append(state.ChunksUploaded, chunk)
+			state.Offset += chunk.Size
+			return saveResumableState(stateFile, state)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	state.Done = true
+	state.ManifestFid = manifestResult.FileID
+	if err = saveResumableState(stateFile, state); err != nil {
+		return nil, err
+	}
+	return manifestResult, nil
+}
+
+// uploadChunksUnsized uploads reader's remaining bytes starting at offset,
+// one Assign per chunk since the total chunk count isn't known up front,
+// and finally writes a chunk manifest covering chunks plus whatever it
+// uploads. chunks holds any chunks already uploaded (offset and len(chunks)
+// must agree, as they do in a resumableUploadState). afterChunk, if
+// non-nil, is called with each newly uploaded chunk - uploadResumable uses
+// it to persist progress; UploadStream passes nil.
+func (c *SwfsClient) uploadChunksUnsized(reader io.Reader, name, mimeType, collection, ttl string, chunkSize, offset int64, chunks []chunkInfo, afterChunk func(chunkInfo) error) (manifestResult *AssignResult, err error) {
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+
+	for {
+		buf := make([]byte, chunkSize)
+		n, readErr := io.ReadFull(reader, buf)
+		if n > 0 {
+			assignResult, assignErr := c.Assign(normalize(nil, collection, ttl))
+			if assignErr != nil {
+				return nil, assignErr
+			}
+
+			base := *c.master
+			base.Host = assignResult.URL
+			chunkName := fmt.Sprintf("%s-chunk-%d", name, len(chunks))
+
+			v, _, uploadErr := c.client.upload(encodeURI(base, assignResult.FileID, normalize(nil, collection, ttl)), chunkName, bytes.NewReader(buf[:n]), mimeType)
+			if uploadErr != nil {
+				return nil, uploadErr
+			}
+
+			uploadResult := UploadResult{}
+			if err = json.Unmarshal(v, &uploadResult); err != nil {
+				return nil, err
+			}
+
+			chunk := chunkInfo{Fid: assignResult.FileID, Offset: offset, Size: int64(n), ETag: uploadResult.Etag}
+			chunks = append(chunks, chunk)
+			offset += int64(n)
+			if afterChunk != nil {
+				if err = afterChunk(chunk); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		} else if readErr != nil {
+			return nil, readErr
+		}
+	}
+
+	manifest := chunkManifest{
+		Name:          name,
+		Mime:          mimeType,
+		Size:          offset,
+		Chunks:        chunks,
+		ChunkManifest: true,
+	}
+	manifestBytes, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	manifestResult, err = c.Assign(normalize(nil, collection, ttl))
+	if err != nil {
+		return nil, err
+	}
+
+	manifestBase := *c.master
+	manifestBase.Host = manifestResult.URL
+	_, _, err = c.client.upload(encodeURI(manifestBase, manifestResult.FileID, normalize(nil, collection, ttl)), name, bytes.NewReader(manifestBytes), "application/json")
+	return manifestResult, err
+}
+
+func saveResumableState(stateFile string, state *resumableUploadState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(stateFile, data, 0644)
+}
+
+func loadResumableState(stateFile string) (*resumableUploadState, error) {
+	data, err := ioutil.ReadFile(stateFile)
+	if err != nil {
+		return nil, err
+	}
+	state := &resumableUploadState{}
+	err = json.Unmarshal(data, state)
+	return state, err
+}
@@ -0,0 +1,118 @@
+package swfsclient
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSeaweedServer is a minimal stand-in for a SeaweedFS master+volume
+// server: every /dir/assign call hands back a fresh fid pointing at itself,
+// and every other path accepts the upload and counts it.
+func fakeSeaweedServer(t *testing.T) (server *httptest.Server, assigns, uploads *int32) {
+	assigns = new(int32)
+	uploads = new(int32)
+
+	mux := http.NewServeMux()
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	mux.HandleFunc("/dir/assign", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(assigns, 1)
+		fmt.Fprintf(w, `{"count":1,"fid":"3,%08x01020304","url":%q,"publicUrl":%q}`, n, host, host)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(uploads, 1)
+		fmt.Fprint(w, `{"name":"chunk","size":1,"eTag":"etag"}`)
+	})
+
+	return server, assigns, uploads
+}
+
+func TestResumableStateRoundTrip(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "upload.swfsupload")
+
+	state := &resumableUploadState{
+		UploadID:   "upload-1",
+		Name:       "big.bin",
+		Mime:       "application/octet-stream",
+		Collection: "photos",
+		TTL:        "3m",
+		ChunkSize:  defaultChunkSize,
+		Offset:     16,
+		ChunksUploaded: []chunkInfo{
+			{Fid: "3,01637037d6", Offset: 0, Size: 8, ETag: "a"},
+			{Fid: "3,02637037d6", Offset: 8, Size: 8, ETag: "b"},
+		},
+	}
+	require.Nil(t, saveResumableState(stateFile, state))
+
+	loaded, err := loadResumableState(stateFile)
+	require.Nil(t, err)
+	require.Equal(t, state, loaded)
+}
+
+func TestUploadStreamAgainstFakeTransport(t *testing.T) {
+	server, assigns, uploads := fakeSeaweedServer(t)
+
+	c, err := NewSwfsClient(server.URL, server.Client(), 0)
+	require.Nil(t, err)
+
+	result, err := c.UploadStream(bytes.NewReader([]byte("hello world")), "stream.bin", "photos", "")
+	require.Nil(t, err)
+	require.NotNil(t, result)
+
+	// One chunk (the data fits in a single defaultChunkSize read) plus the
+	// manifest: two Assign calls, two uploads.
+	require.EqualValues(t, 2, atomic.LoadInt32(assigns))
+	require.EqualValues(t, 2, atomic.LoadInt32(uploads))
+}
+
+func TestUploadResumableAgainstFakeTransport(t *testing.T) {
+	server, assigns, uploads := fakeSeaweedServer(t)
+
+	c, err := NewSwfsClient(server.URL, server.Client(), 0)
+	require.Nil(t, err)
+
+	data := bytes.Repeat([]byte("x"), 10)
+	result, stateFile, err := c.ResumableUpload(bytes.NewReader(data), "stream.bin", "photos", "", 4)
+	if stateFile != "" {
+		t.Cleanup(func() { _ = os.Remove(stateFile) })
+	}
+	require.Nil(t, err)
+	require.NotNil(t, result)
+
+	// 10 bytes in chunks of 4 -> 3 chunk Assigns, plus 1 manifest Assign.
+	require.EqualValues(t, 4, atomic.LoadInt32(assigns))
+	require.EqualValues(t, 4, atomic.LoadInt32(uploads))
+
+	state, err := loadResumableState(stateFile)
+	require.Nil(t, err)
+	require.True(t, state.Done)
+	require.Len(t, state.ChunksUploaded, 3)
+	require.Equal(t, int64(10), state.Offset)
+}
+
+func TestResumeUploadRejectsCompletedState(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "upload.swfsupload")
+
+	state := &resumableUploadState{
+		UploadID: "upload-done",
+		Done:     true,
+	}
+	require.Nil(t, saveResumableState(stateFile, state))
+
+	c := &SwfsClient{}
+	_, err := c.ResumeUpload(stateFile, strings.NewReader("irrelevant"))
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "already complete")
+}
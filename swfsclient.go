@@ -12,6 +12,9 @@ import (
 	"time"
 
 	"github.com/patrickmn/go-cache"
+	"google.golang.org/grpc"
+
+	"github.com/simpcl/seaweedfsclient/filer"
 )
 
 type SwfsClient struct {
@@ -19,6 +22,19 @@ type SwfsClient struct {
 	client               *httpClient
 	maxFileSize          int64
 	volumeLocationsCache *cache.Cache
+	filer                *filer.Filer
+	assignPool           *AssignPool
+	topologyWatchStop    chan struct{}
+
+	// UseGRPC makes Download fetch file bytes over the volume server's gRPC
+	// port instead of HTTP. See DownloadGRPC. One intentional difference
+	// from the HTTP path: the returned fileName is always "", since the
+	// volume_server_pb.FileGet RPC doesn't carry one.
+	UseGRPC bool
+
+	// GrpcDialOption is used when dialing volume servers for gRPC reads.
+	// Defaults to grpc.WithInsecure() if left nil.
+	GrpcDialOption grpc.DialOption
 }
 
 func NewSwfsClient(masterURL string, client *http.Client, fileSizeLimit int64) (c *SwfsClient, err error) {
@@ -38,6 +54,10 @@ func NewSwfsClient(masterURL string, client *http.Client, fileSizeLimit int64) (
 }
 
 func (c *SwfsClient) Close() (err error) {
+	if c.topologyWatchStop != nil {
+		close(c.topologyWatchStop)
+		c.topologyWatchStop = nil
+	}
 	if c.client != nil {
 		err = c.client.Close()
 	}
@@ -45,8 +65,8 @@ func (c *SwfsClient) Close() (err error) {
 }
 
 // Grow pre-Allocate Volumes.
-func (c *SwfsClient) Grow(count int, collection, replication, dataCenter string) error {
-	args := normalize(nil, collection, "")
+func (c *SwfsClient) Grow(count int, collection, ttl, replication, dataCenter string) error {
+	args := normalize(nil, collection, ttl)
 	if count > 0 {
 		args.Set(ParamGrowCount, strconv.Itoa(count))
 	}
@@ -198,6 +218,24 @@ func (c *SwfsClient) ClusterStatus() (result *ClusterStatus, err error) {
 	return
 }
 
+// EnableAssignPool turns on pre-reserved fid pooling: bursty callers should
+// use GetFID afterwards instead of Assign, so they don't hit /dir/assign on
+// every call. poolSize is how many fids to reserve per (collection, ttl,
+// replication, dataCenter) tuple, and lowWaterMark is the remaining-fid
+// threshold that triggers a background refill.
+func (c *SwfsClient) EnableAssignPool(poolSize, lowWaterMark int) {
+	c.assignPool = newAssignPool(c, poolSize, lowWaterMark)
+}
+
+// GetFID hands out a pre-reserved fid for (collection, ttl, replication,
+// dataCenter). Requires EnableAssignPool to have been called first.
+func (c *SwfsClient) GetFID(collection, ttl, replication, dataCenter string) (string, error) {
+	if c.assignPool == nil {
+		return "", errAssignPoolNotEnabled
+	}
+	return c.assignPool.GetFID(collection, ttl, replication, dataCenter)
+}
+
 // Assign do assign api.
 func (c *SwfsClient) Assign(args url.Values) (result *AssignResult, err error) {
 	jsonBlob, _, err := c.client.get(encodeURI(*c.master, "/dir/assign", args), nil)
@@ -291,6 +329,10 @@ func (c *SwfsClient) UploadFile(filePath string, collection, ttl string) (assign
 
 // Download file by id.
 func (c *SwfsClient) Download(fileID string, args url.Values, callback func(io.Reader) error) (string, error) {
+	if c.UseGRPC {
+		return c.DownloadGRPC(fileID, args, callback)
+	}
+
 	var withCache = true
 	var err error = nil
 	for retry := 2; retry > 0; retry-- {
@@ -311,6 +353,29 @@ func (c *SwfsClient) Download(fileID string, args url.Values, callback func(io.R
 	return "", err
 }
 
+// StatFile resolves fileID's volume location and issues a HEAD request
+// against it, returning its size without downloading the body. It retries
+// with cache invalidation the same way Download does, so a rebalance that
+// moved fileID's volume doesn't wedge it on a stale cache entry.
+func (c *SwfsClient) StatFile(fileID string, args url.Values) (size int64, err error) {
+	var withCache = true
+	for retry := 2; retry > 0; retry-- {
+		var vls *VolumeLocations = nil
+		vls, err = c.GetVolumeLocationsFromFileID(fileID, args, withCache)
+		if err != nil {
+			return 0, err
+		}
+
+		fileURL := fmt.Sprintf("http://%s/%s", vls.RandomPickForRead().PublicURL, fileID)
+		size, err = c.client.head(fileURL)
+		if err == nil {
+			return size, nil
+		}
+		withCache = false
+	}
+	return 0, err
+}
+
 // DeleteFile by id.
 func (c *SwfsClient) DeleteFile(fileID string, args url.Values) error {
 	var withCache = true
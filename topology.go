@@ -0,0 +1,130 @@
+package swfsclient
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// defaultTopologyPollInterval is used by WatchMasterTopology when called
+// with pollInterval <= 0.
+const defaultTopologyPollInterval = 30 * time.Second
+
+// InvalidateVolume evicts volID from volumeLocationsCache, for callers that
+// detect staleness out-of-band (e.g. a "file not found" after a rebalance
+// they know about through other means).
+func (c *SwfsClient) InvalidateVolume(volID string) {
+	c.volumeLocationsCache.Delete(volID)
+}
+
+// InvalidateAll evicts every entry from volumeLocationsCache.
+func (c *SwfsClient) InvalidateAll() {
+	c.volumeLocationsCache.Flush()
+}
+
+// WatchMasterTopology polls /dir/status every pollInterval (default 30s)
+// and evicts volumeLocationsCache entries for any volume whose server set
+// changes between polls - either because it disappeared entirely or
+// because it moved to a different data node - so Download/DeleteFile
+// stop relying solely on their retry loop after a rebalance. Call Close
+// to stop the watch.
+func (c *SwfsClient) WatchMasterTopology(pollInterval time.Duration) {
+	if pollInterval <= 0 {
+		pollInterval = defaultTopologyPollInterval
+	}
+
+	c.topologyWatchStop = make(chan struct{})
+	go c.watchMasterTopology(pollInterval, c.topologyWatchStop)
+}
+
+func (c *SwfsClient) watchMasterTopology(pollInterval time.Duration, stopCh <-chan struct{}) {
+	previous, _ := c.fetchVolumeLocations()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			current, err := c.fetchVolumeLocations()
+			if err != nil {
+				continue
+			}
+
+			for volID, servers := range previous {
+				if !sameServerSet(servers, current[volID]) {
+					c.InvalidateVolume(volID)
+				}
+			}
+			previous = current
+		}
+	}
+}
+
+// topologyStatus is the subset of /dir/status this file cares about: which
+// data nodes serve which volumes.
+type topologyStatus struct {
+	Topology struct {
+		DataCenters []struct {
+			Racks []struct {
+				DataNodes []struct {
+					PublicUrl string `json:"PublicUrl"`
+					Volumes   []struct {
+						Id int `json:"Id"`
+					} `json:"Volumes"`
+				} `json:"DataNodes"`
+			} `json:"Racks"`
+		} `json:"DataCenters"`
+	} `json:"Topology"`
+}
+
+// fetchVolumeLocations returns, for every volume ID /dir/status currently
+// reports, the set of data node addresses serving it. A volume that moves
+// to a different node keeps its ID but changes which set this map holds
+// it under, which is what lets watchMasterTopology tell a move apart from
+// a volume that is merely still where it was.
+func (c *SwfsClient) fetchVolumeLocations() (map[string]map[string]bool, error) {
+	data, _, err := c.client.get(encodeURI(*c.master, "/dir/status", nil), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	status := topologyStatus{}
+	if err = json.Unmarshal(data, &status); err != nil {
+		return nil, err
+	}
+
+	volumeServers := make(map[string]map[string]bool)
+	for _, dc := range status.Topology.DataCenters {
+		for _, rack := range dc.Racks {
+			for _, node := range rack.DataNodes {
+				for _, vol := range node.Volumes {
+					volID := strconv.Itoa(vol.Id)
+					if volumeServers[volID] == nil {
+						volumeServers[volID] = make(map[string]bool)
+					}
+					volumeServers[volID][node.PublicUrl] = true
+				}
+			}
+		}
+	}
+	return volumeServers, nil
+}
+
+// sameServerSet reports whether a and b contain the same set of server
+// addresses. A nil b (the volume is missing from the latest successful
+// poll) differs from any non-empty a, so a vanished volume counts as
+// changed.
+func sameServerSet(a, b map[string]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for server := range a {
+		if !b[server] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,27 @@
+package swfsclient
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSameServerSet(t *testing.T) {
+	require.True(t, sameServerSet(nil, nil))
+	require.True(t, sameServerSet(
+		map[string]bool{"10.0.0.1:8080": true},
+		map[string]bool{"10.0.0.1:8080": true},
+	))
+
+	// A volume that moved to a different data node keeps its ID but its
+	// server set changes - this is the case a plain ID diff misses.
+	require.False(t, sameServerSet(
+		map[string]bool{"10.0.0.1:8080": true},
+		map[string]bool{"10.0.0.2:8080": true},
+	))
+
+	require.False(t, sameServerSet(
+		map[string]bool{"10.0.0.1:8080": true},
+		nil,
+	))
+}